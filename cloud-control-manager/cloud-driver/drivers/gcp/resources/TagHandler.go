@@ -12,28 +12,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	cloudresourcemanager "google.golang.org/api/cloudresourcemanager/v3"
 	compute "google.golang.org/api/compute/v1"
 	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/googleapi"
 
 	idrv "github.com/cloud-barista/cb-spider/cloud-control-manager/cloud-driver/interfaces"
 	irs "github.com/cloud-barista/cb-spider/cloud-control-manager/cloud-driver/interfaces/resources"
 )
 
+// findTagConcurrency caps how many of FindTag's per-resource goroutines
+// (one per VM, disk, cluster or NLB backend service returned by a list call)
+// evaluate the keyword at the same time on large projects.
+const findTagConcurrency = 10
+
+// rmTagPrefix marks a KeyValue.Key as addressing a Resource Manager Tag
+// binding (TagKeys/TagValues/TagBindings) rather than a plain GCE label, e.g.
+// "tag:my-org-id/env" with Value "prod". Keys without this prefix keep the
+// existing label behavior.
+const rmTagPrefix = "tag:"
+
 type GCPTagHandler struct {
 	Region     idrv.RegionInfo
 	Ctx        context.Context
 	Credential idrv.CredentialInfo
 
-	ComputeClient   *compute.Service
-	ContainerClient *container.Service
+	ComputeClient              *compute.Service
+	ContainerClient            *container.Service
+	CloudResourceManagerClient *cloudresourcemanager.Service
+
+	// FingerprintRetryCap overrides how many times AddTags/RemoveTags
+	// re-fetch, re-merge, and resubmit a SetLabels/SetResourceLabels call
+	// after a 412 "conditionNotMet" LabelFingerprint conflict. Zero (the
+	// default for callers that don't set it) falls back to
+	// defaultFingerprintRetryCap.
+	FingerprintRetryCap int
 }
 
 var (
 	supportRSType = map[irs.RSType]interface{}{
 		irs.VM: nil, irs.DISK: nil, irs.CLUSTER: nil,
+		irs.NLB: nil, irs.MYIMAGE: nil, irs.VPC: nil, irs.SG: nil,
 	}
 )
 
@@ -63,132 +87,605 @@ func (t *GCPTagHandler) getDisk(resIID irs.IID) (*compute.Disk, error) {
 }
 
 func (t *GCPTagHandler) getCluster(resIID irs.IID) (*container.Cluster, error) {
-	parent := getParentClusterAtContainer(t.Credential.ProjectID, t.Region.Zone, resIID.SystemId)
-	cluster, err := t.ContainerClient.Projects.Locations.Clusters.Get(parent).Do()
+	cluster, _, err := t.getClusterAndLocation(resIID)
+	return cluster, err
+}
+
+// getClusterAndLocation resolves resIID's GKE location once and then fetches
+// the cluster at that location, so callers that need both the cluster and its
+// location (AddTags/RemoveTags) don't pay for resolveClusterLocation's
+// region/zone/wildcard probing a second time just to get the location string.
+func (t *GCPTagHandler) getClusterAndLocation(resIID irs.IID) (*container.Cluster, string, error) {
+	location, err := t.resolveClusterLocation(resIID)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return cluster, nil
-}
-
-func (t *GCPTagHandler) AddTag(resType irs.RSType, resIID irs.IID, tag KeyValue) (KeyValue, error) {
-	err := validateSupportRS(resType)
-	errRes := KeyValue{}
+	parent := getParentClusterAtContainer(t.Credential.ProjectID, location, resIID.SystemId)
+	cluster, err := t.ContainerClient.Projects.Locations.Clusters.Get(parent).Do()
 	if err != nil {
-		return errRes, err
+		return nil, "", err
 	}
 
+	return cluster, location, nil
+}
+
+// resolveClusterLocation finds the GKE location (the bare region name for a
+// regional cluster, the bare zone name for a zonal one) that actually holds
+// resIID. It prefers t.Region.Region first since regional clusters are the
+// common case going forward, falls back to t.Region.Zone, and as a last
+// resort discovers the real location with a wildcard "locations/-" list.
+func (t *GCPTagHandler) resolveClusterLocation(resIID irs.IID) (string, error) {
 	projectId := t.Credential.ProjectID
-	zone := t.Region.Zone
-	switch resType {
-	case irs.VM:
-		vm, err := t.getVm(resIID)
-		if err != nil {
-			return errRes, err
+
+	for _, location := range []string{t.Region.Region, t.Region.Zone} {
+		if location == "" {
+			continue
 		}
 
-		existLabels := vm.Labels
-		existLabels[tag.Key] = tag.Value
+		parent := getParentClusterAtContainer(projectId, location, resIID.SystemId)
+		_, err := t.ContainerClient.Projects.Locations.Clusters.Get(parent).Do()
+		if err == nil {
+			return location, nil
+		}
+		if !isNotFoundError(err) {
+			return "", err
+		}
+	}
 
-		req := &compute.InstancesSetLabelsRequest{
-			LabelFingerprint: vm.Fingerprint,
-			Labels:           existLabels,
+	wildcardParent := fmt.Sprintf("projects/%s/locations/-", projectId)
+	list, err := t.ContainerClient.Projects.Locations.Clusters.List(wildcardParent).Do()
+	if err != nil {
+		return "", err
+	}
+	for _, cluster := range list.Clusters {
+		if cluster.Name == resIID.SystemId {
+			return cluster.Location, nil
 		}
+	}
 
-		op, err := t.ComputeClient.Instances.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+	return "", fmt.Errorf("cluster %s not found in any location", resIID.SystemId)
+}
 
-		if err != nil {
-			return errRes, err
+// rmGlobalLocation marks a Resource Manager resource name as addressing a
+// project-global resource (no zone/region in its path), e.g. a disk
+// snapshot rather than a zonal disk.
+const rmGlobalLocation = "global"
+
+// resourceLocation returns the location a Resource Manager resource name
+// should embed for resType: the configured zone for VM/DISK (or
+// rmGlobalLocation when resIID turns out to be a disk snapshot rather than a
+// zonal disk; any getDisk error is taken to mean a snapshot, without a
+// Snapshots.Get check), or the resolved (possibly regional) GKE location for
+// CLUSTER.
+func (t *GCPTagHandler) resourceLocation(resType irs.RSType, resIID irs.IID) (string, error) {
+	if resType == irs.CLUSTER {
+		return t.resolveClusterLocation(resIID)
+	}
+	if resType == irs.DISK {
+		if _, err := t.getDisk(resIID); err != nil {
+			return rmGlobalLocation, nil
 		}
+	}
+	return t.Region.Zone, nil
+}
 
-		if op.Error != nil {
-			return errRes, fmt.Errorf("operation failed: %v", op.Error.Errors)
-		}
+// defaultFingerprintRetryCap bounds how many times AddTags/RemoveTags
+// re-fetch, re-merge, and resubmit a SetLabels/SetResourceLabels call after a
+// 412 "conditionNotMet" LabelFingerprint conflict, unless a caller overrides
+// it via GCPTagHandler.FingerprintRetryCap.
+const defaultFingerprintRetryCap = 5
 
-		return tag, nil
-	case irs.DISK:
+// AddTag adds a single key/value tag. It's a thin wrapper over AddTags kept
+// for callers that only ever set one tag at a time.
+func (t *GCPTagHandler) AddTag(resType irs.RSType, resIID irs.IID, tag KeyValue) (KeyValue, error) {
+	applied, err := t.AddTags(resType, resIID, []KeyValue{tag})
+	if err != nil {
+		return KeyValue{}, err
+	}
+	if len(applied) == 0 {
+		return KeyValue{}, nil
+	}
+	return applied[0], nil
+}
+
+// AddTags applies every tag in one read-modify-write round-trip per resource
+// instead of one round-trip per key: it fetches the resource once, merges
+// all labels in memory, and submits a single SetLabels/SetResourceLabels
+// call, retrying on a LabelFingerprint conflict (HTTP 412). Resource Manager
+// Tag bindings (rmTagPrefix keys) don't carry a fingerprint, so each is
+// applied as its own TagBindings.Create call.
+func (t *GCPTagHandler) AddTags(resType irs.RSType, resIID irs.IID, tags []KeyValue) ([]KeyValue, error) {
+	if err := validateSupportRS(resType); err != nil {
+		return nil, err
+	}
 
-		disk, err := t.getDisk(resIID)
+	labelTags, rmTags := splitTagsByMode(tags)
+
+	applied := []KeyValue{}
+	for _, tag := range rmTags {
+		parentID, shortName, _ := parseTagKey(tag.Key)
+		kv, err := t.addResourceTagBinding(resType, resIID, parentID, shortName, tag.Value)
 		if err != nil {
-			return errRes, err
+			return applied, err
 		}
+		applied = append(applied, kv)
+	}
 
-		existLabels := disk.Labels
-		existLabels[tag.Key] = tag.Value
+	if len(labelTags) == 0 {
+		return applied, nil
+	}
 
-		req := &compute.ZoneSetLabelsRequest{
-			LabelFingerprint: disk.LabelFingerprint,
-			Labels:           existLabels,
-		}
+	projectId := t.Credential.ProjectID
+	zone := t.Region.Zone
+	err := t.retryOnFingerprintConflict(func() error {
+		switch resType {
+		case irs.VM:
+			vm, err := t.getVm(resIID)
+			if err != nil {
+				return err
+			}
 
-		op, err := t.ComputeClient.Disks.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+			labels := mergeLabels(vm.Labels, labelTags)
+			req := &compute.InstancesSetLabelsRequest{
+				LabelFingerprint: vm.Fingerprint,
+				Labels:           labels,
+			}
+			op, err := t.ComputeClient.Instances.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Errors)
+			}
+			return t.waitForOperation(op)
+		case irs.DISK:
+			disk, diskErr := t.getDisk(resIID)
+			if diskErr == nil {
+				labels := mergeLabels(disk.Labels, labelTags)
+				req := &compute.ZoneSetLabelsRequest{
+					LabelFingerprint: disk.LabelFingerprint,
+					Labels:           labels,
+				}
+				op, err := t.ComputeClient.Disks.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+				if err != nil {
+					return err
+				}
+				if op.Error != nil {
+					return fmt.Errorf("operation failed: %v", op.Error.Errors)
+				}
+				return t.waitForOperation(op)
+			}
 
-		if err != nil {
-			return errRes, err
-		}
+			// Not a disk under this zone - it may be a disk snapshot, which GCE
+			// exposes as its own global resource with its own labels API.
+			snapshot, err := t.ComputeClient.Snapshots.Get(projectId, resIID.SystemId).Do()
+			if err != nil {
+				return diskErr
+			}
 
-		if op.Error != nil {
-			return errRes, fmt.Errorf("operation failed: %v", op.Error.Errors)
-		}
+			labels := mergeLabels(snapshot.Labels, labelTags)
+			req := &compute.GlobalSetLabelsRequest{
+				LabelFingerprint: snapshot.LabelFingerprint,
+				Labels:           labels,
+			}
+			op, err := t.ComputeClient.Snapshots.SetLabels(projectId, resIID.SystemId, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Errors)
+			}
+			return t.waitForGlobalOperation(op)
+		case irs.MYIMAGE:
+			image, err := t.ComputeClient.Images.Get(projectId, resIID.SystemId).Do()
+			if err != nil {
+				return err
+			}
 
-		return tag, nil
-	case irs.CLUSTER:
-		cluster, err := t.getCluster(resIID)
-		if err != nil {
-			return errRes, err
+			labels := mergeLabels(image.Labels, labelTags)
+			req := &compute.GlobalSetLabelsRequest{
+				LabelFingerprint: image.LabelFingerprint,
+				Labels:           labels,
+			}
+			op, err := t.ComputeClient.Images.SetLabels(projectId, resIID.SystemId, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Errors)
+			}
+			return t.waitForGlobalOperation(op)
+		case irs.NLB:
+			return t.addNLBLabels(resIID, labelTags)
+		case irs.VPC:
+			return errors.New("labels are read-only for GCP VPC networks; update the network description directly")
+		case irs.SG:
+			return t.addFirewallTargetTags(resIID, labelTags)
+		case irs.CLUSTER:
+			cluster, location, err := t.getClusterAndLocation(resIID)
+			if err != nil {
+				return err
+			}
+
+			labels := mergeLabels(cluster.ResourceLabels, labelTags)
+			name := getParentClusterAtContainer(projectId, location, resIID.SystemId)
+			req := &container.SetLabelsRequest{
+				ClusterId:        resIID.SystemId,
+				LabelFingerprint: cluster.LabelFingerprint,
+				Name:             name,
+				ProjectId:        projectId,
+				ResourceLabels:   labels,
+			}
+			op, err := t.ContainerClient.Projects.Locations.Clusters.SetResourceLabels(name, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Message)
+			}
+			return t.waitForContainerOperation(op, location)
+		default:
+			return errors.New("unsupported resource type")
 		}
+	})
+	if err != nil {
+		return applied, err
+	}
 
-		existLabels := cluster.ResourceLabels
-		existLabels[tag.Key] = tag.Value
+	return append(applied, labelTags...), nil
+}
 
-		name := getParentClusterAtContainer(projectId, zone, resIID.SystemId)
-		req := &container.SetLabelsRequest{
-			ClusterId:        resIID.SystemId,
-			LabelFingerprint: cluster.LabelFingerprint,
-			Name:             name,
-			ProjectId:        projectId,
-			Zone:             zone,
-			ResourceLabels:   existLabels,
+// splitTagsByMode separates rmTagPrefix-addressed Resource Manager Tags from
+// plain GCE labels, since they're applied through different APIs.
+func splitTagsByMode(tags []KeyValue) (labelTags, rmTags []KeyValue) {
+	for _, tag := range tags {
+		if _, _, ok := parseTagKey(tag.Key); ok {
+			rmTags = append(rmTags, tag)
+		} else {
+			labelTags = append(labelTags, tag)
 		}
-		op, err := t.ContainerClient.Projects.Locations.Clusters.SetResourceLabels(name, req).Do()
+	}
+	return labelTags, rmTags
+}
 
-		if err != nil {
-			return errRes, err
-		}
+// mergeLabels applies tags on top of a resource's existing labels in memory
+// so the caller can submit a single SetLabels call for all of them.
+func mergeLabels(existing map[string]string, tags []KeyValue) map[string]string {
+	labels := existing
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for _, tag := range tags {
+		labels[tag.Key] = tag.Value
+	}
+	return labels
+}
+
+// retryOnFingerprintConflict retries fn, which performs a fetch-merge-submit
+// label update, when it fails with a 412 LabelFingerprint conflict. It backs
+// off exponentially starting at 500ms, up to FingerprintRetryCap retries
+// (or defaultFingerprintRetryCap if unset) after the initial attempt.
+func (t *GCPTagHandler) retryOnFingerprintConflict(fn func() error) error {
+	retryCap := t.FingerprintRetryCap
+	if retryCap <= 0 {
+		retryCap = defaultFingerprintRetryCap
+	}
 
-		if op.Error != nil {
-			return errRes, fmt.Errorf("operation failed: %v", op.Error.Message)
+	backoff := 500 * time.Millisecond
+	var err error
+	for attempt := 0; attempt <= retryCap; attempt++ {
+		err = fn()
+		if err == nil || !isFingerprintConflict(err) {
+			return err
 		}
 
-		return tag, nil
-	default:
-		return tag, errors.New("unsupported resource type")
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return err
+}
+
+// isFingerprintConflict reports whether err is the HTTP 412 "conditionNotMet"
+// the compute/container API returns when a SetLabels call races a concurrent
+// LabelFingerprint change.
+func isFingerprintConflict(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusPreconditionFailed
+	}
+	return false
+}
+
+// isNotFoundError reports whether err is a plain HTTP 404 from the GCP API,
+// as opposed to an auth/quota/transient failure that a caller probing
+// several locations (resolveClusterLocation) should surface immediately
+// instead of silently treating as "try the next location".
+func isNotFoundError(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusNotFound
 	}
+	return false
 }
 
 func (t *GCPTagHandler) waitForOperation(o *compute.Operation) error {
 	cnt := 10
 	projectID := t.Credential.ProjectID
 	zone := t.Region.Zone
-	for cnt < 0 {
-		if strings.ToUpper(o.Status) == "DONE" {
-			if o.Error != nil {
-				return fmt.Errorf("operation failed: %v", o.Error.Errors)
-			}
-			return nil
+	for strings.ToUpper(o.Status) != "DONE" && cnt > 0 {
+		time.Sleep(2 * time.Second)
+		op, err := t.ComputeClient.ZoneOperations.Get(projectID, zone, o.Name).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get operation status: %v", err)
+		}
+		o = op
+		cnt--
+	}
+
+	if strings.ToUpper(o.Status) != "DONE" {
+		return errors.New("operation has not been finished.")
+	}
+	if o.Error != nil {
+		return fmt.Errorf("operation failed: %v", o.Error.Errors)
+	}
+
+	return nil
+}
+
+// waitForContainerOperation mirrors waitForOperation for the GKE API, whose
+// long-running operations live under a location rather than a zone.
+func (t *GCPTagHandler) waitForContainerOperation(o *container.Operation, location string) error {
+	cnt := 10
+	parent := fmt.Sprintf("projects/%s/locations/%s/operations/%s", t.Credential.ProjectID, location, o.Name)
+	for strings.ToUpper(o.Status) != "DONE" && cnt > 0 {
+		time.Sleep(2 * time.Second)
+		op, err := t.ContainerClient.Projects.Locations.Operations.Get(parent).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get operation status: %v", err)
 		}
+		o = op
+		cnt--
+	}
+
+	if strings.ToUpper(o.Status) != "DONE" {
+		return errors.New("operation has not been finished.")
+	}
+	if o.Error != nil {
+		return fmt.Errorf("operation failed: %v", o.Error.Message)
+	}
 
+	return nil
+}
+
+// waitForGlobalOperation mirrors waitForOperation for project-global compute
+// resources (images, snapshots, firewalls) that have no zone or region.
+func (t *GCPTagHandler) waitForGlobalOperation(o *compute.Operation) error {
+	cnt := 10
+	projectID := t.Credential.ProjectID
+	for strings.ToUpper(o.Status) != "DONE" && cnt > 0 {
 		time.Sleep(2 * time.Second)
-		op, err := t.ComputeClient.ZoneOperations.Get(projectID, zone, o.Name).Do()
+		op, err := t.ComputeClient.GlobalOperations.Get(projectID, o.Name).Do()
 		if err != nil {
 			return fmt.Errorf("failed to get operation status: %v", err)
 		}
+		o = op
 		cnt--
+	}
+
+	if strings.ToUpper(o.Status) != "DONE" {
+		return errors.New("operation has not been finished.")
+	}
+	if o.Error != nil {
+		return fmt.Errorf("operation failed: %v", o.Error.Errors)
+	}
+
+	return nil
+}
+
+// waitForRegionOperation mirrors waitForOperation for regional compute
+// resources (NLB backend services and forwarding rules).
+func (t *GCPTagHandler) waitForRegionOperation(o *compute.Operation, region string) error {
+	cnt := 10
+	projectID := t.Credential.ProjectID
+	for strings.ToUpper(o.Status) != "DONE" && cnt > 0 {
+		time.Sleep(2 * time.Second)
+		op, err := t.ComputeClient.RegionOperations.Get(projectID, region, o.Name).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get operation status: %v", err)
+		}
 		o = op
+		cnt--
+	}
+
+	if strings.ToUpper(o.Status) != "DONE" {
+		return errors.New("operation has not been finished.")
+	}
+	if o.Error != nil {
+		return fmt.Errorf("operation failed: %v", o.Error.Errors)
+	}
+
+	return nil
+}
+
+// addNLBLabels applies labelTags to both halves of a GCP passthrough NLB -
+// the regional backend service and its forwarding rule - since each carries
+// its own labels and LabelFingerprint.
+func (t *GCPTagHandler) addNLBLabels(resIID irs.IID, labelTags []KeyValue) error {
+	projectId := t.Credential.ProjectID
+	region := t.Region.Region
+
+	bs, err := t.ComputeClient.RegionBackendServices.Get(projectId, region, resIID.SystemId).Do()
+	if err != nil {
+		return err
+	}
+
+	bsReq := &compute.RegionSetLabelsRequest{
+		LabelFingerprint: bs.LabelFingerprint,
+		Labels:           mergeLabels(bs.Labels, labelTags),
+	}
+	op, err := t.ComputeClient.RegionBackendServices.SetLabels(projectId, region, resIID.SystemId, bsReq).Do()
+	if err != nil {
+		return err
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Errors)
+	}
+	if err := t.waitForRegionOperation(op, region); err != nil {
+		return err
+	}
+
+	fr, err := t.ComputeClient.ForwardingRules.Get(projectId, region, resIID.SystemId).Do()
+	if err != nil {
+		return err
+	}
+
+	frReq := &compute.RegionSetLabelsRequest{
+		LabelFingerprint: fr.LabelFingerprint,
+		Labels:           mergeLabels(fr.Labels, labelTags),
+	}
+	op, err = t.ComputeClient.ForwardingRules.SetLabels(projectId, region, resIID.SystemId, frReq).Do()
+	if err != nil {
+		return err
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Errors)
+	}
+	return t.waitForRegionOperation(op, region)
+}
+
+// removeNLBLabels mirrors addNLBLabels for label removal.
+func (t *GCPTagHandler) removeNLBLabels(resIID irs.IID, keys []string) error {
+	projectId := t.Credential.ProjectID
+	region := t.Region.Region
+
+	bs, err := t.ComputeClient.RegionBackendServices.Get(projectId, region, resIID.SystemId).Do()
+	if err != nil {
+		return err
+	}
+
+	bsReq := &compute.RegionSetLabelsRequest{
+		LabelFingerprint: bs.LabelFingerprint,
+		Labels:           removeLabels(bs.Labels, keys),
+	}
+	op, err := t.ComputeClient.RegionBackendServices.SetLabels(projectId, region, resIID.SystemId, bsReq).Do()
+	if err != nil {
+		return err
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Errors)
+	}
+	if err := t.waitForRegionOperation(op, region); err != nil {
+		return err
+	}
+
+	fr, err := t.ComputeClient.ForwardingRules.Get(projectId, region, resIID.SystemId).Do()
+	if err != nil {
+		return err
+	}
+
+	frReq := &compute.RegionSetLabelsRequest{
+		LabelFingerprint: fr.LabelFingerprint,
+		Labels:           removeLabels(fr.Labels, keys),
+	}
+	op, err = t.ComputeClient.ForwardingRules.SetLabels(projectId, region, resIID.SystemId, frReq).Do()
+	if err != nil {
+		return err
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Errors)
+	}
+	return t.waitForRegionOperation(op, region)
+}
+
+// addFirewallTargetTags treats tags as GCE network tags (firewalls carry no
+// labels) and merges them into the firewall's targetTags via Firewalls.Patch.
+func (t *GCPTagHandler) addFirewallTargetTags(resIID irs.IID, tags []KeyValue) error {
+	projectId := t.Credential.ProjectID
+
+	fw, err := t.ComputeClient.Firewalls.Get(projectId, resIID.SystemId).Do()
+	if err != nil {
+		return err
+	}
+
+	merged := map[string]bool{}
+	for _, existing := range fw.TargetTags {
+		merged[existing] = true
+	}
+	for _, tag := range tags {
+		merged[tag.Key] = true
 	}
 
-	return errors.New("operation has not been finished.")
+	targetTags := make([]string, 0, len(merged))
+	for tg := range merged {
+		targetTags = append(targetTags, tg)
+	}
+
+	op, err := t.ComputeClient.Firewalls.Patch(projectId, resIID.SystemId, &compute.Firewall{TargetTags: targetTags}).Do()
+	if err != nil {
+		return err
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Errors)
+	}
+	return t.waitForGlobalOperation(op)
+}
+
+// removeFirewallTargetTags mirrors addFirewallTargetTags for tag removal.
+func (t *GCPTagHandler) removeFirewallTargetTags(resIID irs.IID, keys []string) error {
+	projectId := t.Credential.ProjectID
+
+	fw, err := t.ComputeClient.Firewalls.Get(projectId, resIID.SystemId).Do()
+	if err != nil {
+		return err
+	}
+
+	remove := map[string]bool{}
+	for _, key := range keys {
+		remove[key] = true
+	}
+
+	targetTags := make([]string, 0, len(fw.TargetTags))
+	for _, existing := range fw.TargetTags {
+		if !remove[existing] {
+			targetTags = append(targetTags, existing)
+		}
+	}
+
+	op, err := t.ComputeClient.Firewalls.Patch(projectId, resIID.SystemId, &compute.Firewall{
+		TargetTags: targetTags,
+		// ForceSendFields is required here: the generated Firewall struct tags
+		// TargetTags with `omitempty`, so an empty-but-non-nil slice (the last
+		// tag being removed) would otherwise be dropped from the PATCH body
+		// and silently leave the firewall's existing tags untouched.
+		ForceSendFields: []string{"TargetTags"},
+	}).Do()
+	if err != nil {
+		return err
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Errors)
+	}
+	return t.waitForGlobalOperation(op)
+}
+
+// parseDescriptionLabels recovers a best-effort label view from a GCE
+// Network's Description field (e.g. "env=prod,team=infra"), since
+// compute.Network carries no Labels field of its own and VPC labels are
+// therefore read-only through this handler.
+func parseDescriptionLabels(description string) []KeyValue {
+	res := []KeyValue{}
+	for _, pair := range strings.Split(description, ",") {
+		idx := strings.Index(pair, "=")
+		if idx < 0 {
+			continue
+		}
+		res = append(res, KeyValue{
+			Key:   strings.TrimSpace(pair[:idx]),
+			Value: strings.TrimSpace(pair[idx+1:]),
+		})
+	}
+	return res
 }
 
 func (t *GCPTagHandler) ListTag(resType irs.RSType, resIID irs.IID) ([]KeyValue, error) {
@@ -213,23 +710,68 @@ func (t *GCPTagHandler) ListTag(resType irs.RSType, resIID irs.IID) ([]KeyValue,
 			}
 			res = append(res, kv)
 		}
-		return res, nil
 	case irs.DISK:
-		disk, err := GetDiskInfo(t.ComputeClient, t.Credential, t.Region, resIID.SystemId)
+		disk, diskErr := GetDiskInfo(t.ComputeClient, t.Credential, t.Region, resIID.SystemId)
+		if diskErr == nil {
+			for k, v := range disk.Labels {
+				res = append(res, KeyValue{Key: k, Value: v})
+			}
+			break
+		}
+
+		snapshot, err := t.ComputeClient.Snapshots.Get(projectID, resIID.SystemId).Do()
+		if err != nil {
+			return res, diskErr
+		}
+		for k, v := range snapshot.Labels {
+			res = append(res, KeyValue{Key: k, Value: v})
+		}
+	case irs.MYIMAGE:
+		image, err := t.ComputeClient.Images.Get(projectID, resIID.SystemId).Do()
 		if err != nil {
 			return res, err
 		}
+		for k, v := range image.Labels {
+			res = append(res, KeyValue{Key: k, Value: v})
+		}
+	case irs.NLB:
+		region := t.Region.Region
+		bs, err := t.ComputeClient.RegionBackendServices.Get(projectID, region, resIID.SystemId).Do()
+		if err != nil {
+			return res, err
+		}
+		for k, v := range bs.Labels {
+			res = append(res, KeyValue{Key: k, Value: v})
+		}
 
-		for k, v := range disk.Labels {
-			kv := KeyValue{
-				Key:   k,
-				Value: v,
-			}
-			res = append(res, kv)
+		fr, err := t.ComputeClient.ForwardingRules.Get(projectID, region, resIID.SystemId).Do()
+		if err != nil {
+			return res, err
+		}
+		for k, v := range fr.Labels {
+			res = append(res, KeyValue{Key: k, Value: v})
+		}
+	case irs.VPC:
+		network, err := t.ComputeClient.Networks.Get(projectID, resIID.SystemId).Do()
+		if err != nil {
+			return res, err
+		}
+		res = append(res, parseDescriptionLabels(network.Description)...)
+	case irs.SG:
+		fw, err := t.ComputeClient.Firewalls.Get(projectID, resIID.SystemId).Do()
+		if err != nil {
+			return res, err
+		}
+		for _, tg := range fw.TargetTags {
+			res = append(res, KeyValue{Key: tg})
 		}
-		return res, nil
 	case irs.CLUSTER:
-		parent := getParentClusterAtContainer(projectID, zone, resIID.SystemId)
+		location, err := t.resolveClusterLocation(resIID)
+		if err != nil {
+			return res, err
+		}
+
+		parent := getParentClusterAtContainer(projectID, location, resIID.SystemId)
 		cluster, err := t.ContainerClient.Projects.Locations.Clusters.Get(parent).Do()
 		if err != nil {
 			return res, err
@@ -242,16 +784,36 @@ func (t *GCPTagHandler) ListTag(resType irs.RSType, resIID irs.IID) ([]KeyValue,
 			}
 			res = append(res, kv)
 		}
-		return res, nil
 	default:
 		return res, errors.New("unsupport resources type")
 	}
-}
-func (t *GCPTagHandler) GetTag(resType irs.RSType, resIID irs.IID, key string) (KeyValue, error) {
-	labels, err := t.ListTag(resType, resIID)
-	res := KeyValue{}
+
+	// Merge in Resource Manager Tag bindings alongside the GCE labels above,
+	// marking their origin with the rmTagPrefix so AddTag/RemoveTag can
+	// round-trip them back through the same "tag:<parent>/<key>" addressing.
+	location, err := t.resourceLocation(resType, resIID)
 	if err != nil {
-		return res, err
+		return res, nil
+	}
+
+	resourceName, err := t.getRMResourceName(resType, resIID, location)
+	if err != nil {
+		return res, nil
+	}
+
+	bindings, err := t.listTagBindings(resourceName)
+	if err != nil {
+		return res, nil
+	}
+	res = append(res, bindings...)
+
+	return res, nil
+}
+func (t *GCPTagHandler) GetTag(resType irs.RSType, resIID irs.IID, key string) (KeyValue, error) {
+	labels, err := t.ListTag(resType, resIID)
+	res := KeyValue{}
+	if err != nil {
+		return res, err
 	}
 
 	for _, l := range labels {
@@ -264,155 +826,856 @@ func (t *GCPTagHandler) GetTag(resType irs.RSType, resIID irs.IID, key string) (
 
 	return res, nil
 }
+
+// RemoveTag removes a single key. It's a thin wrapper over RemoveTags kept
+// for callers that only ever remove one tag at a time.
 func (t *GCPTagHandler) RemoveTag(resType irs.RSType, resIID irs.IID, key string) (bool, error) {
-	err := validateSupportRS(resType)
-	if err != nil {
+	return t.RemoveTags(resType, resIID, []string{key})
+}
+
+// RemoveTags removes every key in one read-modify-write round-trip per
+// resource instead of one round-trip per key, retrying on a LabelFingerprint
+// conflict (HTTP 412) the same way AddTags does.
+func (t *GCPTagHandler) RemoveTags(resType irs.RSType, resIID irs.IID, keys []string) (bool, error) {
+	if err := validateSupportRS(resType); err != nil {
 		return false, err
 	}
 
+	var labelKeys []string
+	for _, key := range keys {
+		if parentID, shortName, ok := parseTagKey(key); ok {
+			if _, err := t.removeResourceTagBinding(resType, resIID, parentID, shortName); err != nil {
+				return false, err
+			}
+			continue
+		}
+		labelKeys = append(labelKeys, key)
+	}
+
+	if len(labelKeys) == 0 {
+		return true, nil
+	}
+
 	projectId := t.Credential.ProjectID
 	zone := t.Region.Zone
+	err := t.retryOnFingerprintConflict(func() error {
+		switch resType {
+		case irs.VM:
+			vm, err := t.getVm(resIID)
+			if err != nil {
+				return err
+			}
+
+			labels := removeLabels(vm.Labels, labelKeys)
+			req := &compute.InstancesSetLabelsRequest{
+				LabelFingerprint: vm.Fingerprint,
+				Labels:           labels,
+			}
+			op, err := t.ComputeClient.Instances.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Errors)
+			}
+			return t.waitForOperation(op)
+		case irs.DISK:
+			disk, diskErr := t.getDisk(resIID)
+			if diskErr == nil {
+				labels := removeLabels(disk.Labels, labelKeys)
+				req := &compute.ZoneSetLabelsRequest{
+					LabelFingerprint: disk.LabelFingerprint,
+					Labels:           labels,
+				}
+				op, err := t.ComputeClient.Disks.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+				if err != nil {
+					return err
+				}
+				if op.Error != nil {
+					return fmt.Errorf("operation failed: %v", op.Error.Errors)
+				}
+				return t.waitForOperation(op)
+			}
+
+			snapshot, err := t.ComputeClient.Snapshots.Get(projectId, resIID.SystemId).Do()
+			if err != nil {
+				return diskErr
+			}
+
+			labels := removeLabels(snapshot.Labels, labelKeys)
+			req := &compute.GlobalSetLabelsRequest{
+				LabelFingerprint: snapshot.LabelFingerprint,
+				Labels:           labels,
+			}
+			op, err := t.ComputeClient.Snapshots.SetLabels(projectId, resIID.SystemId, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Errors)
+			}
+			return t.waitForGlobalOperation(op)
+		case irs.MYIMAGE:
+			image, err := t.ComputeClient.Images.Get(projectId, resIID.SystemId).Do()
+			if err != nil {
+				return err
+			}
+
+			labels := removeLabels(image.Labels, labelKeys)
+			req := &compute.GlobalSetLabelsRequest{
+				LabelFingerprint: image.LabelFingerprint,
+				Labels:           labels,
+			}
+			op, err := t.ComputeClient.Images.SetLabels(projectId, resIID.SystemId, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Errors)
+			}
+			return t.waitForGlobalOperation(op)
+		case irs.NLB:
+			return t.removeNLBLabels(resIID, labelKeys)
+		case irs.VPC:
+			return errors.New("labels are read-only for GCP VPC networks; update the network description directly")
+		case irs.SG:
+			return t.removeFirewallTargetTags(resIID, labelKeys)
+		case irs.CLUSTER:
+			cluster, location, err := t.getClusterAndLocation(resIID)
+			if err != nil {
+				return err
+			}
+
+			labels := removeLabels(cluster.ResourceLabels, labelKeys)
+			name := getParentClusterAtContainer(projectId, location, resIID.SystemId)
+			req := &container.SetLabelsRequest{
+				ClusterId:        resIID.SystemId,
+				LabelFingerprint: cluster.LabelFingerprint,
+				Name:             name,
+				ProjectId:        projectId,
+				ResourceLabels:   labels,
+			}
+			op, err := t.ContainerClient.Projects.Locations.Clusters.SetResourceLabels(name, req).Do()
+			if err != nil {
+				return err
+			}
+			if op.Error != nil {
+				return fmt.Errorf("operation failed: %v", op.Error.Message)
+			}
+			return t.waitForContainerOperation(op, location)
+		default:
+			return errors.New("unsupported resource type")
+		}
+	})
+
+	return err == nil, err
+}
+
+// removeLabels deletes keys from a resource's existing labels in memory so
+// the caller can submit a single SetLabels call for all of them.
+func removeLabels(existing map[string]string, keys []string) map[string]string {
+	labels := existing
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for _, key := range keys {
+		delete(labels, key)
+	}
+	return labels
+}
+
+// parseTagKey splits a "tag:<parentID>/<shortName>" key into its Resource
+// Manager Tag parts. ok is false for plain label keys (no rmTagPrefix),
+// which keeps the existing label code path unchanged.
+func parseTagKey(key string) (parentID string, shortName string, ok bool) {
+	if !strings.HasPrefix(key, rmTagPrefix) {
+		return "", "", false
+	}
+
+	rest := strings.TrimPrefix(key, rmTagPrefix)
+	idx := strings.LastIndex(rest, "/")
+	if idx < 0 {
+		return "", rest, true
+	}
+
+	return rest[:idx], rest[idx+1:], true
+}
+
+// getRMResourceName builds the fully qualified Resource Manager resource
+// name a TagBinding's Parent must reference for the given resource type.
+func (t *GCPTagHandler) getRMResourceName(resType irs.RSType, resIID irs.IID, location string) (string, error) {
+	projectId := t.Credential.ProjectID
 	switch resType {
 	case irs.VM:
-		vm, err := t.getVm(resIID)
-		if err != nil {
-			return false, err
+		return fmt.Sprintf("//compute.googleapis.com/projects/%s/zones/%s/instances/%s", projectId, location, resIID.SystemId), nil
+	case irs.DISK:
+		if location == rmGlobalLocation {
+			return fmt.Sprintf("//compute.googleapis.com/projects/%s/global/snapshots/%s", projectId, resIID.SystemId), nil
 		}
+		return fmt.Sprintf("//compute.googleapis.com/projects/%s/zones/%s/disks/%s", projectId, location, resIID.SystemId), nil
+	case irs.CLUSTER:
+		return fmt.Sprintf("//container.googleapis.com/projects/%s/locations/%s/clusters/%s", projectId, location, resIID.SystemId), nil
+	default:
+		return "", errors.New("unsupported resource type")
+	}
+}
 
-		existLabels := vm.Labels
-		if _, ok := existLabels[key]; ok {
-			delete(existLabels, key)
+// waitForRMOperation polls a Resource Manager long-running operation until
+// it finishes, mirroring waitForOperation's capped, fixed-interval polling
+// for the cloudresourcemanager API.
+func (t *GCPTagHandler) waitForRMOperation(op *cloudresourcemanager.Operation) error {
+	cnt := 10
+	for !op.Done && cnt > 0 {
+		time.Sleep(2 * time.Second)
+
+		refreshed, err := t.CloudResourceManagerClient.Operations.Get(op.Name).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get operation status: %v", err)
 		}
+		op = refreshed
+		cnt--
+	}
 
-		req := &compute.InstancesSetLabelsRequest{
-			LabelFingerprint: vm.Fingerprint,
-			Labels:           existLabels,
+	if !op.Done {
+		return errors.New("operation has not been finished.")
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation failed: %v", op.Error.Message)
+	}
+
+	return nil
+}
+
+// resolveTagKey looks up an existing TagKey by its namespaced name
+// ("<parentID>/<shortName>") and creates it under parentID when missing.
+func (t *GCPTagHandler) resolveTagKey(parentID, shortName string) (*cloudresourcemanager.TagKey, error) {
+	namespacedName := fmt.Sprintf("%s/%s", parentID, shortName)
+
+	tagKey, err := t.CloudResourceManagerClient.TagKeys.GetNamespacedName().Name(namespacedName).Do()
+	if err == nil {
+		return tagKey, nil
+	}
+
+	op, err := t.CloudResourceManagerClient.TagKeys.Create(&cloudresourcemanager.TagKey{
+		Parent:    parentID,
+		ShortName: shortName,
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.waitForRMOperation(op); err != nil {
+		return nil, err
+	}
+
+	return t.CloudResourceManagerClient.TagKeys.GetNamespacedName().Name(namespacedName).Do()
+}
+
+// resolveTagValue mirrors resolveTagKey one level down, under the resolved
+// TagKey.
+func (t *GCPTagHandler) resolveTagValue(tagKey *cloudresourcemanager.TagKey, value string) (*cloudresourcemanager.TagValue, error) {
+	namespacedName := fmt.Sprintf("%s/%s", tagKey.NamespacedName, value)
+
+	tagValue, err := t.CloudResourceManagerClient.TagValues.GetNamespacedName().Name(namespacedName).Do()
+	if err == nil {
+		return tagValue, nil
+	}
+
+	op, err := t.CloudResourceManagerClient.TagValues.Create(&cloudresourcemanager.TagValue{
+		Parent:    tagKey.Name,
+		ShortName: value,
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.waitForRMOperation(op); err != nil {
+		return nil, err
+	}
+
+	return t.CloudResourceManagerClient.TagValues.GetNamespacedName().Name(namespacedName).Do()
+}
+
+// listTagBindings returns every TagBinding on resourceName as KeyValue pairs
+// addressed the same way AddTag/RemoveTag accept them, so the result
+// round-trips.
+func (t *GCPTagHandler) listTagBindings(resourceName string) ([]KeyValue, error) {
+	res := []KeyValue{}
+
+	bindings, err := t.CloudResourceManagerClient.TagBindings.List().Parent(resourceName).Do()
+	if err != nil {
+		return res, err
+	}
+
+	for _, b := range bindings.TagBindings {
+		parts := strings.SplitN(b.TagValueNamespacedName, "/", 3)
+		if len(parts) != 3 {
+			continue
 		}
 
-		op, err := t.ComputeClient.Instances.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+		res = append(res, KeyValue{
+			Key:   fmt.Sprintf("%s%s/%s", rmTagPrefix, parts[0], parts[1]),
+			Value: parts[2],
+		})
+	}
+
+	return res, nil
+}
+
+func (t *GCPTagHandler) addResourceTagBinding(resType irs.RSType, resIID irs.IID, parentID, shortName, value string) (KeyValue, error) {
+	errRes := KeyValue{}
+
+	location, err := t.resourceLocation(resType, resIID)
+	if err != nil {
+		return errRes, err
+	}
+
+	resourceName, err := t.getRMResourceName(resType, resIID, location)
+	if err != nil {
+		return errRes, err
+	}
+
+	tagKey, err := t.resolveTagKey(parentID, shortName)
+	if err != nil {
+		return errRes, err
+	}
+
+	tagValue, err := t.resolveTagValue(tagKey, value)
+	if err != nil {
+		return errRes, err
+	}
+
+	op, err := t.CloudResourceManagerClient.TagBindings.Create(&cloudresourcemanager.TagBinding{
+		Parent:   resourceName,
+		TagValue: tagValue.Name,
+	}).Do()
+	if err != nil {
+		return errRes, err
+	}
+	if err := t.waitForRMOperation(op); err != nil {
+		return errRes, err
+	}
+
+	return KeyValue{Key: fmt.Sprintf("%s%s/%s", rmTagPrefix, parentID, shortName), Value: value}, nil
+}
+
+func (t *GCPTagHandler) removeResourceTagBinding(resType irs.RSType, resIID irs.IID, parentID, shortName string) (bool, error) {
+	location, err := t.resourceLocation(resType, resIID)
+	if err != nil {
+		return false, err
+	}
+
+	resourceName, err := t.getRMResourceName(resType, resIID, location)
+	if err != nil {
+		return false, err
+	}
 
+	bindings, err := t.CloudResourceManagerClient.TagBindings.List().Parent(resourceName).Do()
+	if err != nil {
+		return false, err
+	}
+
+	prefix := fmt.Sprintf("%s/%s/", parentID, shortName)
+	for _, b := range bindings.TagBindings {
+		if !strings.HasPrefix(b.TagValueNamespacedName, prefix) {
+			continue
+		}
+
+		op, err := t.CloudResourceManagerClient.TagBindings.Delete(b.Name).Do()
 		if err != nil {
 			return false, err
 		}
-
-		if op.Error != nil {
-			return false, fmt.Errorf("operation failed: %v", op.Error.Errors)
+		if err := t.waitForRMOperation(op); err != nil {
+			return false, err
 		}
 
 		return true, nil
+	}
+
+	return false, nil
+}
+
+// buildLabelFilter turns a FindTag keyword into a GCE list-filter expression
+// when the keyword parses as "key=value" (e.g. "env=prod" -> "labels.env=prod").
+// It returns "" when the keyword can't be expressed server-side, in which
+// case FindTag falls back to a client-side substring match via matchLabels.
+func buildLabelFilter(keyword string) string {
+	// rmTagPrefix-addressed keywords (e.g. "tag:123456789/env=prod") name a
+	// Resource Manager Tag binding, not a GCE label, and can't be pushed down
+	// as a GCE label filter - matchResourceTags handles them client-side via
+	// listTagBindings instead.
+	if strings.HasPrefix(keyword, rmTagPrefix) {
+		return ""
+	}
+	if idx := strings.Index(keyword, "="); idx > 0 {
+		return fmt.Sprintf("labels.%s=%s", keyword[:idx], keyword[idx+1:])
+	}
+	return ""
+}
+
+// matchLabels re-applies keyword against a resource's labels and returns the
+// matching KeyValue subset. For "key=value" keywords this is an exact match
+// on that single pair; otherwise it's a substring match on both keys and
+// values, mirroring the semantics buildLabelFilter could not push to GCE.
+func matchLabels(labels map[string]string, keyword string) []KeyValue {
+	matched := []KeyValue{}
+	if idx := strings.Index(keyword, "="); idx > 0 {
+		key, value := keyword[:idx], keyword[idx+1:]
+		if v, ok := labels[key]; ok && v == value {
+			matched = append(matched, KeyValue{Key: key, Value: v})
+		}
+		return matched
+	}
+
+	for k, v := range labels {
+		if strings.Contains(k, keyword) || strings.Contains(v, keyword) {
+			matched = append(matched, KeyValue{Key: k, Value: v})
+		}
+	}
+	return matched
+}
+
+// matchResourceTags dispatches a FindTag keyword to a Resource Manager Tag
+// binding search (keyword prefixed with rmTagPrefix) or the plain label
+// match, so FindTag results merge both origins the same way ListTag does.
+func (t *GCPTagHandler) matchResourceTags(resourceName string, labels map[string]string, keyword string) []KeyValue {
+	if !strings.HasPrefix(keyword, rmTagPrefix) {
+		return matchLabels(labels, keyword)
+	}
+
+	matched := []KeyValue{}
+	bindings, err := t.listTagBindings(resourceName)
+	if err != nil {
+		return matched
+	}
+
+	rest := strings.TrimPrefix(keyword, rmTagPrefix)
+	if idx := strings.Index(rest, "="); idx > 0 {
+		key, value := rest[:idx], rest[idx+1:]
+		for _, kv := range bindings {
+			if strings.TrimPrefix(kv.Key, rmTagPrefix) == key && kv.Value == value {
+				matched = append(matched, kv)
+			}
+		}
+		return matched
+	}
+
+	for _, kv := range bindings {
+		bareKey := strings.TrimPrefix(kv.Key, rmTagPrefix)
+		if strings.Contains(bareKey, rest) || strings.Contains(kv.Value, rest) {
+			matched = append(matched, kv)
+		}
+	}
+	return matched
+}
+
+func (t *GCPTagHandler) FindTag(resType irs.RSType, keyword string) ([]*irs.TagInfo, error) {
+	err := validateSupportRS(resType)
+	errRes := []*irs.TagInfo{}
+	if err != nil {
+		return errRes, err
+	}
+
+	projectId := t.Credential.ProjectID
+	switch resType {
+	case irs.VM:
+		return t.findVMTag(projectId, keyword)
 	case irs.DISK:
+		return t.findDiskTag(projectId, keyword)
+	case irs.CLUSTER:
+		return t.findClusterTag(projectId, keyword)
+	case irs.MYIMAGE:
+		return t.findMyImageTag(projectId, keyword)
+	case irs.NLB:
+		return t.findNLBTag(projectId, keyword)
+	case irs.VPC:
+		return t.findVPCTag(projectId, keyword)
+	case irs.SG:
+		return t.findSGTag(projectId, keyword)
+	default:
+		return errRes, errors.New("unsupported resources type")
+	}
+}
 
-		disk, err := t.getDisk(resIID)
+// findVMTag scans every zone in the project in a single call via
+// Instances.AggregatedList instead of looping t.Region.Zone, pushing the
+// keyword down as a GCE filter when possible.
+func (t *GCPTagHandler) findVMTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+	filter := buildLabelFilter(keyword)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, findTagConcurrency)
+
+	pageToken := ""
+	for {
+		call := t.ComputeClient.Instances.AggregatedList(projectId)
+		if filter != "" {
+			call = call.Filter(filter)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		list, err := call.Do()
 		if err != nil {
-			return false, err
+			return res, err
 		}
 
-		existLabels := disk.Labels
-		if _, ok := existLabels[key]; ok {
-			delete(existLabels, key)
+		for zoneKey, scoped := range list.Items {
+			zone := strings.TrimPrefix(zoneKey, "zones/")
+			for _, vm := range scoped.Instances {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(zone string, vm *compute.Instance) {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					resourceName, _ := t.getRMResourceName(irs.VM, irs.IID{SystemId: vm.Name}, zone)
+					matched := t.matchResourceTags(resourceName, vm.Labels, keyword)
+					if len(matched) == 0 {
+						return
+					}
+
+					mu.Lock()
+					res = append(res, &irs.TagInfo{
+						ResType: irs.VM,
+						ResIId: irs.IID{
+							NameId:   vm.Name,
+							SystemId: vm.Name,
+						},
+						TagList: matched,
+					})
+					mu.Unlock()
+				}(zone, vm)
+			}
 		}
-		req := &compute.ZoneSetLabelsRequest{
-			LabelFingerprint: disk.LabelFingerprint,
-			Labels:           existLabels,
+
+		if list.NextPageToken == "" {
+			break
 		}
+		pageToken = list.NextPageToken
+	}
+	wg.Wait()
 
-		op, err := t.ComputeClient.Disks.SetLabels(projectId, zone, resIID.SystemId, req).Do()
+	return res, nil
+}
+
+// findDiskTag mirrors findVMTag for persistent disks via Disks.AggregatedList.
+func (t *GCPTagHandler) findDiskTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+	filter := buildLabelFilter(keyword)
 
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, findTagConcurrency)
+
+	pageToken := ""
+	for {
+		call := t.ComputeClient.Disks.AggregatedList(projectId)
+		if filter != "" {
+			call = call.Filter(filter)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		list, err := call.Do()
 		if err != nil {
-			return false, err
+			return res, err
 		}
 
-		if op.Error != nil {
-			return false, fmt.Errorf("operation failed: %v", op.Error.Errors)
+		for zoneKey, scoped := range list.Items {
+			zone := strings.TrimPrefix(zoneKey, "zones/")
+			for _, disk := range scoped.Disks {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(zone string, disk *compute.Disk) {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					resourceName, _ := t.getRMResourceName(irs.DISK, irs.IID{SystemId: disk.Name}, zone)
+					matched := t.matchResourceTags(resourceName, disk.Labels, keyword)
+					if len(matched) == 0 {
+						return
+					}
+
+					mu.Lock()
+					res = append(res, &irs.TagInfo{
+						ResType: irs.DISK,
+						ResIId: irs.IID{
+							NameId:   disk.Name,
+							SystemId: disk.Name,
+						},
+						TagList: matched,
+					})
+					mu.Unlock()
+				}(zone, disk)
+			}
 		}
 
-		return true, nil
-	case irs.CLUSTER:
-		cluster, err := t.getCluster(resIID)
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
+	}
+	wg.Wait()
+
+	return res, nil
+}
+
+// findClusterTag walks every region/zone at once via the "-" wildcard
+// location so both zonal and regional GKE clusters are discovered, instead
+// of being limited to t.Region.Zone. The GKE List API has no page token.
+func (t *GCPTagHandler) findClusterTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+	parent := fmt.Sprintf("projects/%s/locations/-", projectId)
+
+	list, err := t.ContainerClient.Projects.Locations.Clusters.List(parent).Do()
+	if err != nil {
+		return res, err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, findTagConcurrency)
+
+	for _, cluster := range list.Clusters {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(cluster *container.Cluster) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resourceName, _ := t.getRMResourceName(irs.CLUSTER, irs.IID{SystemId: cluster.Name}, cluster.Location)
+			matched := t.matchResourceTags(resourceName, cluster.ResourceLabels, keyword)
+			if len(matched) == 0 {
+				return
+			}
+
+			mu.Lock()
+			res = append(res, &irs.TagInfo{
+				ResType: irs.CLUSTER,
+				ResIId: irs.IID{
+					NameId:   cluster.Name,
+					SystemId: cluster.Name,
+				},
+				TagList: matched,
+			})
+			mu.Unlock()
+		}(cluster)
+	}
+	wg.Wait()
+
+	return res, nil
+}
+
+// findMyImageTag scans every custom image in the project via Images.List,
+// the same label-filter-and-fallback shape findVMTag/findDiskTag use.
+// Resource Manager Tag bindings aren't wired up for MyImage/NLB/VPC/SG (see
+// getRMResourceName), so this matches plain GCE labels only.
+func (t *GCPTagHandler) findMyImageTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+	filter := buildLabelFilter(keyword)
+
+	pageToken := ""
+	for {
+		call := t.ComputeClient.Images.List(projectId)
+		if filter != "" {
+			call = call.Filter(filter)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		list, err := call.Do()
 		if err != nil {
-			return false, err
+			return res, err
+		}
+
+		for _, image := range list.Items {
+			matched := matchLabels(image.Labels, keyword)
+			if len(matched) == 0 {
+				continue
+			}
+			res = append(res, &irs.TagInfo{
+				ResType: irs.MYIMAGE,
+				ResIId: irs.IID{
+					NameId:   image.Name,
+					SystemId: image.Name,
+				},
+				TagList: matched,
+			})
 		}
 
-		existLabels := cluster.ResourceLabels
-		if _, ok := existLabels[key]; ok {
-			delete(existLabels, key)
+		if list.NextPageToken == "" {
+			break
 		}
+		pageToken = list.NextPageToken
+	}
+
+	return res, nil
+}
 
-		name := getParentClusterAtContainer(projectId, zone, resIID.SystemId)
-		req := &container.SetLabelsRequest{
-			ClusterId:        resIID.SystemId,
-			LabelFingerprint: cluster.LabelFingerprint,
-			Name:             name,
-			ProjectId:        projectId,
-			Zone:             zone,
-			ResourceLabels:   existLabels,
+// findNLBTag scans every regional backend service in t.Region.Region and
+// merges in its paired forwarding rule's labels (the two resources an NLB is
+// made of), matching the combined label set the same way ListTag's irs.NLB
+// case reports them.
+func (t *GCPTagHandler) findNLBTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+	region := t.Region.Region
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, findTagConcurrency)
+
+	pageToken := ""
+	for {
+		call := t.ComputeClient.RegionBackendServices.List(projectId, region)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
 		}
-		op, err := t.ContainerClient.Projects.Locations.Clusters.SetResourceLabels(name, req).Do()
 
+		list, err := call.Do()
 		if err != nil {
-			return false, err
+			return res, err
 		}
 
-		if op.Error != nil {
-			return false, fmt.Errorf("operation failed: %v", op.Error.Message)
+		for _, bs := range list.Items {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(bs *compute.BackendService) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				labels := map[string]string{}
+				for k, v := range bs.Labels {
+					labels[k] = v
+				}
+				if fr, err := t.ComputeClient.ForwardingRules.Get(projectId, region, bs.Name).Do(); err == nil {
+					for k, v := range fr.Labels {
+						labels[k] = v
+					}
+				}
+
+				matched := matchLabels(labels, keyword)
+				if len(matched) == 0 {
+					return
+				}
+
+				mu.Lock()
+				res = append(res, &irs.TagInfo{
+					ResType: irs.NLB,
+					ResIId: irs.IID{
+						NameId:   bs.Name,
+						SystemId: bs.Name,
+					},
+					TagList: matched,
+				})
+				mu.Unlock()
+			}(bs)
 		}
 
-		return true, nil
-	default:
-		return false, errors.New("unsupported resource type")
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
 	}
+	wg.Wait()
+
+	return res, nil
 }
-func (t *GCPTagHandler) FindTag(resType irs.RSType, keyword string) ([]*irs.TagInfo, error) {
-	// err := validateSupportRS(resType)
-	// errRes := []*irs.TagInfo{}
-	// if err != nil {
-	// 	return errRes, err
-	// }
-
-	// projectId := t.Credential.ProjectID
-	// zone := t.Region.Zone
-	// switch resType {
-	// case irs.VM:
-	// 	vms, err := t.ComputeClient.Instances.List(projectId, zone).Do()
-	// 	if err != nil {
-	// 		return errRes, err
-	// 	}
-
-	// 	for _, i := range vms.Items {
-	// 		irs.TagInfo{
-	// 			ResType: resType,
-	// 			ResIId: irs.IID{
-	// 				NameId: "",
-	// 				SystemId: "",
-	// 			},
-	// 		}
-	// 		for k, v := range i.Labels {
-	// 			if strings.Contains(k, keyword) || strings.Contains(v, keyword) {
-
-	// 					irs.KeyValue{
-
-	// 					}
-
-	// 			}
-	// 		}
-	// 	}
-
-	// case irs.DISK:
-	// 	disks, err := t.ComputeClient.Disks.List(projectId, zone).Do()
-	// 	if err != nil {
-	// 		return errRes, err
-	// 	}
-
-	// case irs.CLUSTER:
-	// 	parent := getParentAtContainer(projectId, zone)
-	// 	clusters, err := t.ContainerClient.Projects.Locations.Clusters.List(parent).Do()
-	// 	if err != nil {
-	// 		return errRes, err
-	// 	}
-
-	// default:
-
-	// }
-
-	return []*irs.TagInfo{}, nil
+
+// findVPCTag scans every VPC network in the project. Networks carry no
+// Labels field, so the keyword is matched client-side against the
+// Description-derived labels parseDescriptionLabels recovers; there is no
+// server-side filter to push down.
+func (t *GCPTagHandler) findVPCTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+
+	pageToken := ""
+	for {
+		call := t.ComputeClient.Networks.List(projectId)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		list, err := call.Do()
+		if err != nil {
+			return res, err
+		}
+
+		for _, network := range list.Items {
+			labels := map[string]string{}
+			for _, kv := range parseDescriptionLabels(network.Description) {
+				labels[kv.Key] = kv.Value
+			}
+
+			matched := matchLabels(labels, keyword)
+			if len(matched) == 0 {
+				continue
+			}
+			res = append(res, &irs.TagInfo{
+				ResType: irs.VPC,
+				ResIId: irs.IID{
+					NameId:   network.Name,
+					SystemId: network.Name,
+				},
+				TagList: matched,
+			})
+		}
+
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
+	}
+
+	return res, nil
+}
+
+// findSGTag scans every firewall rule in the project and matches the keyword
+// against its targetTags (firewalls carry network tags, not labels, so there
+// is no key=value pair — only the tag name itself, returned as KeyValue.Key).
+func (t *GCPTagHandler) findSGTag(projectId, keyword string) ([]*irs.TagInfo, error) {
+	res := []*irs.TagInfo{}
+
+	pageToken := ""
+	for {
+		call := t.ComputeClient.Firewalls.List(projectId)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		list, err := call.Do()
+		if err != nil {
+			return res, err
+		}
+
+		for _, fw := range list.Items {
+			matched := []KeyValue{}
+			for _, tg := range fw.TargetTags {
+				if strings.Contains(tg, keyword) {
+					matched = append(matched, KeyValue{Key: tg})
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			res = append(res, &irs.TagInfo{
+				ResType: irs.SG,
+				ResIId: irs.IID{
+					NameId:   fw.Name,
+					SystemId: fw.Name,
+				},
+				TagList: matched,
+			})
+		}
+
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
+	}
+
+	return res, nil
 }
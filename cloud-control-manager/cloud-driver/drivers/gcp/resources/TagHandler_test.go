@@ -0,0 +1,336 @@
+// Proof of Concepts of CB-Spider.
+// The CB-Spider is a sub-Framework of the Cloud-Barista Multi-Cloud Project.
+// The CB-Spider Mission is to connect all the clouds with a single interface.
+//
+//      * Cloud-Barista: https://github.com/cloud-barista
+//
+// This is a Cloud Driver
+
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	compute "google.golang.org/api/compute/v1"
+	container "google.golang.org/api/container/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	idrv "github.com/cloud-barista/cb-spider/cloud-control-manager/cloud-driver/interfaces"
+	irs "github.com/cloud-barista/cb-spider/cloud-control-manager/cloud-driver/interfaces/resources"
+)
+
+// newFakeContainerHandler builds a GCPTagHandler whose ContainerClient talks
+// to an httptest.Server running handler, so resolveClusterLocation/getCluster
+// can be exercised without a real GKE project.
+func newFakeContainerHandler(t *testing.T, region idrv.RegionInfo, handler http.HandlerFunc) *GCPTagHandler {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	svc, err := container.NewService(context.Background(),
+		option.WithEndpoint(server.URL),
+		option.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("failed to build fake container service: %v", err)
+	}
+
+	return &GCPTagHandler{
+		Region:          region,
+		Ctx:             context.Background(),
+		Credential:      idrv.CredentialInfo{ProjectID: "test-project"},
+		ContainerClient: svc,
+	}
+}
+
+func writeCluster(w http.ResponseWriter, name, location string) {
+	_ = json.NewEncoder(w).Encode(&container.Cluster{Name: name, Location: location})
+}
+
+func TestResolveClusterLocation_Zonal(t *testing.T) {
+	region := idrv.RegionInfo{Zone: "asia-northeast2-a"}
+
+	handler := newFakeContainerHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		want := fmt.Sprintf("/v1/projects/test-project/locations/%s/clusters/my-cluster", region.Zone)
+		if r.URL.Path != want {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		writeCluster(w, "my-cluster", region.Zone)
+	})
+
+	location, err := handler.resolveClusterLocation(irs.IID{SystemId: "my-cluster"})
+	if err != nil {
+		t.Fatalf("resolveClusterLocation returned error: %v", err)
+	}
+	if location != region.Zone {
+		t.Errorf("location = %q, want %q", location, region.Zone)
+	}
+}
+
+func TestResolveClusterLocation_Regional(t *testing.T) {
+	region := idrv.RegionInfo{Region: "asia-northeast2", Zone: "asia-northeast2-a"}
+
+	handler := newFakeContainerHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/v1/projects/test-project/locations/%s/clusters/my-cluster", region.Region):
+			writeCluster(w, "my-cluster", region.Region)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+
+	location, err := handler.resolveClusterLocation(irs.IID{SystemId: "my-cluster"})
+	if err != nil {
+		t.Fatalf("resolveClusterLocation returned error: %v", err)
+	}
+	if location != region.Region {
+		t.Errorf("location = %q, want %q", location, region.Region)
+	}
+}
+
+func TestResolveClusterLocation_WildcardFallback(t *testing.T) {
+	region := idrv.RegionInfo{Region: "asia-northeast2", Zone: "asia-northeast2-a"}
+	const actualLocation = "asia-northeast3"
+
+	handler := newFakeContainerHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/test-project/locations/-/clusters":
+			_ = json.NewEncoder(w).Encode(&container.ListClustersResponse{
+				Clusters: []*container.Cluster{{Name: "my-cluster", Location: actualLocation}},
+			})
+		default:
+			// Neither the configured region nor zone holds this cluster.
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+
+	location, err := handler.resolveClusterLocation(irs.IID{SystemId: "my-cluster"})
+	if err != nil {
+		t.Fatalf("resolveClusterLocation returned error: %v", err)
+	}
+	if location != actualLocation {
+		t.Errorf("location = %q, want %q", location, actualLocation)
+	}
+}
+
+func TestResolveClusterLocation_NotFound(t *testing.T) {
+	region := idrv.RegionInfo{Region: "asia-northeast2", Zone: "asia-northeast2-a"}
+
+	handler := newFakeContainerHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/projects/test-project/locations/-/clusters":
+			_ = json.NewEncoder(w).Encode(&container.ListClustersResponse{})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+
+	if _, err := handler.resolveClusterLocation(irs.IID{SystemId: "missing-cluster"}); err == nil {
+		t.Fatal("expected an error for a cluster that exists nowhere, got nil")
+	}
+}
+
+func TestResolveClusterLocation_NonNotFoundErrorStopsProbing(t *testing.T) {
+	region := idrv.RegionInfo{Region: "asia-northeast2", Zone: "asia-northeast2-a"}
+
+	var zoneProbed bool
+	handler := newFakeContainerHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/v1/projects/test-project/locations/%s/clusters/my-cluster", region.Region):
+			// Simulate a permissions/quota failure on the region probe, not a
+			// "this cluster isn't regional" 404.
+			http.Error(w, "permission denied", http.StatusForbidden)
+		case fmt.Sprintf("/v1/projects/test-project/locations/%s/clusters/my-cluster", region.Zone):
+			zoneProbed = true
+			writeCluster(w, "my-cluster", region.Zone)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+
+	if _, err := handler.resolveClusterLocation(irs.IID{SystemId: "my-cluster"}); err == nil {
+		t.Fatal("expected a 403 from the region probe to be surfaced, got nil")
+	}
+	if zoneProbed {
+		t.Error("resolveClusterLocation fell through to the zone probe after a non-404 error instead of returning it immediately")
+	}
+}
+
+func TestGetCluster_UsesResolvedLocation(t *testing.T) {
+	region := idrv.RegionInfo{Region: "asia-northeast2", Zone: "asia-northeast2-a"}
+
+	handler := newFakeContainerHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/v1/projects/test-project/locations/%s/clusters/my-cluster", region.Region):
+			writeCluster(w, "my-cluster", region.Region)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+
+	cluster, err := handler.getCluster(irs.IID{SystemId: "my-cluster"})
+	if err != nil {
+		t.Fatalf("getCluster returned error: %v", err)
+	}
+	if cluster.Location != region.Region {
+		t.Errorf("cluster.Location = %q, want %q", cluster.Location, region.Region)
+	}
+}
+
+// newFakeComputeHandler builds a GCPTagHandler whose ComputeClient talks to
+// an httptest.Server running handler, so waitForOperation's polling loop can
+// be exercised without a real GCE project.
+func newFakeComputeHandler(t *testing.T, region idrv.RegionInfo, handler http.HandlerFunc) *GCPTagHandler {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	svc, err := compute.NewService(context.Background(),
+		option.WithEndpoint(server.URL),
+		option.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("failed to build fake compute service: %v", err)
+	}
+
+	return &GCPTagHandler{
+		Region:        region,
+		Ctx:           context.Background(),
+		Credential:    idrv.CredentialInfo{ProjectID: "test-project"},
+		ComputeClient: svc,
+	}
+}
+
+// TestWaitForOperation_DoneOnLastPoll reproduces the bug fixed in
+// 17fcc8c: waitForOperation used to check o.Status against the operation
+// fetched on the *previous* iteration, so a completion observed on the very
+// last allowed poll (the 10th ZoneOperations.Get call) was never inspected
+// and the call was reported as "operation has not been finished." even
+// though it had actually succeeded.
+func TestWaitForOperation_DoneOnLastPoll(t *testing.T) {
+	region := idrv.RegionInfo{Zone: "asia-northeast2-a"}
+
+	var gets int
+	handler := newFakeComputeHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		op := &compute.Operation{Name: "op-1", Status: "PENDING"}
+		if gets == 10 {
+			op.Status = "DONE"
+		}
+		_ = json.NewEncoder(w).Encode(op)
+	})
+
+	err := handler.waitForOperation(&compute.Operation{Name: "op-1", Status: "PENDING"})
+	if err != nil {
+		t.Fatalf("waitForOperation returned error: %v", err)
+	}
+	if gets != 10 {
+		t.Errorf("ZoneOperations.Get was called %d times, want exactly 10", gets)
+	}
+}
+
+func TestWaitForOperation_OperationError(t *testing.T) {
+	region := idrv.RegionInfo{Zone: "asia-northeast2-a"}
+
+	handler := newFakeComputeHandler(t, region, func(w http.ResponseWriter, r *http.Request) {
+		op := &compute.Operation{
+			Name:   "op-1",
+			Status: "DONE",
+			Error: &compute.OperationError{
+				Errors: []*compute.OperationErrorErrors{{Code: "RESOURCE_ERROR", Message: "boom"}},
+			},
+		}
+		_ = json.NewEncoder(w).Encode(op)
+	})
+
+	err := handler.waitForOperation(&compute.Operation{Name: "op-1", Status: "PENDING"})
+	if err == nil {
+		t.Fatal("expected the operation's Error field to surface as an error, got nil")
+	}
+}
+
+func TestIsFingerprintConflict(t *testing.T) {
+	if isFingerprintConflict(nil) {
+		t.Error("isFingerprintConflict(nil) = true, want false")
+	}
+	if isFingerprintConflict(errors.New("boom")) {
+		t.Error("isFingerprintConflict on a plain error = true, want false")
+	}
+	if !isFingerprintConflict(&googleapi.Error{Code: http.StatusPreconditionFailed}) {
+		t.Error("isFingerprintConflict on a 412 googleapi.Error = false, want true")
+	}
+	if isFingerprintConflict(&googleapi.Error{Code: http.StatusNotFound}) {
+		t.Error("isFingerprintConflict on a 404 googleapi.Error = true, want false")
+	}
+}
+
+// TestRetryOnFingerprintConflict_RetriesOn412 guards the retry loop that
+// AddTags/RemoveTags rely on to survive a concurrent LabelFingerprint
+// update: the first attempt fails with a 412, the second succeeds, and the
+// helper must retry exactly once and return the eventual nil error.
+func TestRetryOnFingerprintConflict_RetriesOn412(t *testing.T) {
+	handler := &GCPTagHandler{}
+
+	attempts := 0
+	err := handler.retryOnFingerprintConflict(func() error {
+		attempts++
+		if attempts == 1 {
+			return &googleapi.Error{Code: http.StatusPreconditionFailed}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retryOnFingerprintConflict returned error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("fn was called %d times, want exactly 2", attempts)
+	}
+}
+
+// TestRetryOnFingerprintConflict_NonConflictErrorStopsImmediately asserts a
+// non-412 error is surfaced without retrying, since retrying a non-transient
+// failure would just waste the caller's remaining fingerprint-retry budget.
+func TestRetryOnFingerprintConflict_NonConflictErrorStopsImmediately(t *testing.T) {
+	handler := &GCPTagHandler{}
+
+	attempts := 0
+	wantErr := errors.New("boom")
+	err := handler.retryOnFingerprintConflict(func() error {
+		attempts++
+		return wantErr
+	})
+	if err != wantErr {
+		t.Fatalf("retryOnFingerprintConflict error = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Errorf("fn was called %d times, want exactly 1", attempts)
+	}
+}
+
+// TestRetryOnFingerprintConflict_RespectsOverrideCap asserts
+// FingerprintRetryCap bounds the retry loop instead of the package default.
+func TestRetryOnFingerprintConflict_RespectsOverrideCap(t *testing.T) {
+	handler := &GCPTagHandler{FingerprintRetryCap: 1}
+
+	attempts := 0
+	err := handler.retryOnFingerprintConflict(func() error {
+		attempts++
+		return &googleapi.Error{Code: http.StatusPreconditionFailed}
+	})
+	if err == nil {
+		t.Fatal("expected the persistent 412 to still be returned once the cap is exhausted, got nil")
+	}
+	if attempts != 2 {
+		t.Errorf("fn was called %d times, want exactly 2 (1 initial attempt + FingerprintRetryCap retries)", attempts)
+	}
+}